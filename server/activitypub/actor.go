@@ -0,0 +1,101 @@
+// Package activitypub turns the server into a minimal Fediverse actor that
+// announces new chapters as they're imported, following the shape of
+// jsonpub-style ActivityPub bridges: a single actor, a poller watching
+// DataDir for changes, and HTTP Signature delivery to followers recorded
+// via Follow activities.
+package activitypub
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+)
+
+const actorUsername = "manga"
+
+// Actor is the server's single ActivityPub identity. It owns the RSA
+// keypair used to sign outgoing deliveries and the on-disk state (outbox,
+// followers, chapter snapshot) under DataDir/.ap/.
+type Actor struct {
+	DataDir    string // the manga library root; actor state lives in DataDir/.ap
+	Domain     string // canonical hostname used to build actor/object IDs
+	PrivateKey *rsa.PrivateKey
+	PublicKey  string // PEM-encoded public key
+	HTTPClient *http.Client
+
+	// followers is the single followerStore instance for this actor, shared
+	// (and its mutex actually exclusive) across every request and the
+	// poller, rather than a fresh unlocked instance per call.
+	followers *followerStore
+}
+
+// NewActor loads or generates the actor's keypair under dataDir/.ap and
+// returns an Actor for domain.
+func NewActor(dataDir, domain string) (*Actor, error) {
+	stateDir := filepath.Join(dataDir, ".ap")
+	priv, pub, err := loadOrGenerateKeys(stateDir)
+	if err != nil {
+		return nil, fmt.Errorf("activitypub keys: %w", err)
+	}
+
+	return &Actor{
+		DataDir:    dataDir,
+		Domain:     domain,
+		PrivateKey: priv,
+		PublicKey:  pub,
+		HTTPClient: safeHTTPClient(),
+		followers:  newFollowerStore(stateDir),
+	}, nil
+}
+
+func (a *Actor) stateDir() string { return filepath.Join(a.DataDir, ".ap") }
+
+// ID is the actor's canonical IRI.
+func (a *Actor) ID() string { return fmt.Sprintf("https://%s/ap/actor", a.Domain) }
+
+func (a *Actor) inboxURL() string  { return fmt.Sprintf("https://%s/ap/inbox", a.Domain) }
+func (a *Actor) outboxURL() string { return fmt.Sprintf("https://%s/ap/outbox", a.Domain) }
+func (a *Actor) keyID() string     { return a.ID() + "#main-key" }
+
+type actorDocument struct {
+	Context           []string          `json:"@context"`
+	ID                string            `json:"id"`
+	Type              string            `json:"type"`
+	PreferredUsername string            `json:"preferredUsername"`
+	Name              string            `json:"name"`
+	Inbox             string            `json:"inbox"`
+	Outbox            string            `json:"outbox"`
+	PublicKey         actorPublicKeyDoc `json:"publicKey"`
+}
+
+type actorPublicKeyDoc struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// ServeActor handles GET /ap/actor, returning the actor's JSON-LD document.
+func (a *Actor) ServeActor(w http.ResponseWriter, r *http.Request) {
+	doc := actorDocument{
+		Context: []string{
+			"https://www.w3.org/ns/activitystreams",
+			"https://w3id.org/security/v1",
+		},
+		ID:                a.ID(),
+		Type:              "Service",
+		PreferredUsername: actorUsername,
+		Name:              "Manga Library",
+		Inbox:             a.inboxURL(),
+		Outbox:            a.outboxURL(),
+		PublicKey: actorPublicKeyDoc{
+			ID:           a.keyID(),
+			Owner:        a.ID(),
+			PublicKeyPem: a.PublicKey,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/activity+json")
+	json.NewEncoder(w).Encode(doc)
+}
@@ -0,0 +1,90 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const followersFile = "followers.json"
+
+// follower is a remote actor that has Followed us, recorded so the poller
+// knows where to deliver new-chapter notes.
+type follower struct {
+	ActorID string `json:"actor_id"`
+	Inbox   string `json:"inbox"`
+}
+
+// followerStore is the persisted, mutex-guarded set of current followers.
+type followerStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newFollowerStore(stateDir string) *followerStore {
+	return &followerStore{path: filepath.Join(stateDir, followersFile)}
+}
+
+func (s *followerStore) list() ([]follower, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked()
+}
+
+func (s *followerStore) add(f follower) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	followers, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	for _, existing := range followers {
+		if existing.ActorID == f.ActorID {
+			return nil
+		}
+	}
+	followers = append(followers, f)
+	return s.writeLocked(followers)
+}
+
+func (s *followerStore) remove(actorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	followers, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	kept := followers[:0]
+	for _, f := range followers {
+		if f.ActorID != actorID {
+			kept = append(kept, f)
+		}
+	}
+	return s.writeLocked(kept)
+}
+
+func (s *followerStore) readLocked() ([]follower, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var followers []follower
+	if err := json.Unmarshal(data, &followers); err != nil {
+		return nil, err
+	}
+	return followers, nil
+}
+
+func (s *followerStore) writeLocked(followers []follower) error {
+	data, err := json.MarshalIndent(followers, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
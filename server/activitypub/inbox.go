@@ -0,0 +1,114 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// incomingActivity is the subset of an ActivityStreams activity we care
+// about: who sent it, what kind it is, and (for Undo) the activity it
+// wraps.
+type incomingActivity struct {
+	Type   string          `json:"type"`
+	Actor  string          `json:"actor"`
+	Object json.RawMessage `json:"object"`
+}
+
+// ServeInbox handles POST /ap/inbox. It tracks Follow/Undo{Follow}
+// activities so the poller knows who to deliver new-chapter notes to, and
+// ignores everything else (we don't accept or display incoming Notes).
+func (a *Actor) ServeInbox(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	var activity incomingActivity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		http.Error(w, "Invalid activity", http.StatusBadRequest)
+		return
+	}
+
+	if activity.Actor == "" {
+		http.Error(w, "Activity missing actor", http.StatusBadRequest)
+		return
+	}
+	remoteActor, err := a.verifyInboundSignature(r, body, activity.Actor)
+	if err != nil {
+		log.Printf("activitypub: rejecting unsigned/invalid activity from %s: %v", activity.Actor, err)
+		http.Error(w, "Invalid or missing signature", http.StatusUnauthorized)
+		return
+	}
+
+	store := a.followers
+
+	switch activity.Type {
+	case "Follow":
+		if err := store.add(follower{ActorID: activity.Actor, Inbox: remoteActor.Inbox}); err != nil {
+			http.Error(w, "Failed to record follower", http.StatusInternalServerError)
+			return
+		}
+
+		accept := map[string]interface{}{
+			"@context": "https://www.w3.org/ns/activitystreams",
+			"id":       fmt.Sprintf("%s#accept-%s", a.ID(), activity.Actor),
+			"type":     "Accept",
+			"actor":    a.ID(),
+			"object":   json.RawMessage(body),
+		}
+		if err := deliver(a, remoteActor.Inbox, accept); err != nil {
+			log.Printf("activitypub: failed to send Accept to %s: %v", activity.Actor, err)
+		}
+
+	case "Undo":
+		var inner incomingActivity
+		if json.Unmarshal(activity.Object, &inner) == nil && inner.Type == "Follow" {
+			if err := store.remove(activity.Actor); err != nil {
+				http.Error(w, "Failed to remove follower", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+type remoteActorDoc struct {
+	Inbox     string               `json:"inbox"`
+	PublicKey remoteActorPublicKey `json:"publicKey"`
+}
+
+type remoteActorPublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// fetchActor dereferences a remote actor IRI to find its inbox URL.
+func fetchActor(client *http.Client, actorID string) (*remoteActorDoc, error) {
+	req, err := http.NewRequest(http.MethodGet, actorID, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/activity+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching %s", resp.StatusCode, actorID)
+	}
+
+	var doc remoteActorDoc
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
@@ -0,0 +1,72 @@
+package activitypub
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	keyBits        = 2048
+	privateKeyFile = "private.pem"
+	publicKeyFile  = "public.pem"
+)
+
+// loadOrGenerateKeys reads an RSA keypair from dir, generating and
+// persisting a new one if none exists yet.
+func loadOrGenerateKeys(dir string) (*rsa.PrivateKey, string, error) {
+	privPath := filepath.Join(dir, privateKeyFile)
+	pubPath := filepath.Join(dir, publicKeyFile)
+
+	if priv, err := readPrivateKey(privPath); err == nil {
+		pubPEM, err := os.ReadFile(pubPath)
+		if err != nil {
+			return nil, "", fmt.Errorf("read public key: %w", err)
+		}
+		return priv, string(pubPEM), nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, "", fmt.Errorf("create key dir: %w", err)
+	}
+
+	priv, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate key: %w", err)
+	}
+
+	privPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(priv),
+	})
+	if err := os.WriteFile(privPath, privPEM, 0o600); err != nil {
+		return nil, "", fmt.Errorf("write private key: %w", err)
+	}
+
+	pubBytes, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal public key: %w", err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubBytes})
+	if err := os.WriteFile(pubPath, pubPEM, 0o644); err != nil {
+		return nil, "", fmt.Errorf("write public key: %w", err)
+	}
+
+	return priv, string(pubPEM), nil
+}
+
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", path)
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
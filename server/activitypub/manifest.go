@@ -0,0 +1,45 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/smoreg/mangaoff-server/handlers"
+)
+
+// loadManifest reads a manifest.json directly from disk. The poller runs
+// outside any HTTP request, so it doesn't go through the cache.Cache that
+// fronts MangaHandler's manifest reads.
+func loadManifest(path string) (*handlers.Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest handlers.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// scanManifests returns every manga manifest found directly under dataDir.
+func scanManifests(dataDir string) ([]*handlers.Manifest, error) {
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests []*handlers.Manifest
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		m, err := loadManifest(filepath.Join(dataDir, e.Name(), "manifest.json"))
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
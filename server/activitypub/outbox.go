@@ -0,0 +1,82 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const outboxFile = "outbox.json"
+
+// outboxStore is the persisted, append-only log of activities this actor
+// has published.
+type outboxStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func newOutboxStore(stateDir string) *outboxStore {
+	return &outboxStore{path: filepath.Join(stateDir, outboxFile)}
+}
+
+func (s *outboxStore) append(activity map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	activities, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	activities = append(activities, activity)
+
+	data, err := json.MarshalIndent(activities, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+func (s *outboxStore) list() ([]map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readLocked()
+}
+
+func (s *outboxStore) readLocked() ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var activities []map[string]interface{}
+	if err := json.Unmarshal(data, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// ServeOutbox handles GET /ap/outbox, returning every published activity as
+// an OrderedCollection.
+func (a *Actor) ServeOutbox(w http.ResponseWriter, r *http.Request) {
+	store := newOutboxStore(a.stateDir())
+	activities, err := store.list()
+	if err != nil {
+		http.Error(w, "Failed to read outbox", http.StatusInternalServerError)
+		return
+	}
+
+	collection := map[string]interface{}{
+		"@context":     "https://www.w3.org/ns/activitystreams",
+		"id":           a.outboxURL(),
+		"type":         "OrderedCollection",
+		"totalItems":   len(activities),
+		"orderedItems": activities,
+	}
+
+	w.Header().Set("Content-Type", "application/activity+json")
+	json.NewEncoder(w).Encode(collection)
+}
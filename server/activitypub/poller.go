@@ -0,0 +1,170 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const snapshotFile = "snapshot.json"
+
+// Poller periodically scans DataDir for chapters that weren't there on the
+// previous scan and announces them as Create{Note} activities.
+type Poller struct {
+	Actor    *Actor
+	Interval time.Duration
+}
+
+// NewPoller returns a Poller that scans actor.DataDir every interval.
+func NewPoller(actor *Actor, interval time.Duration) *Poller {
+	return &Poller{Actor: actor, Interval: interval}
+}
+
+// Run blocks, scanning on every tick until ctx is canceled.
+func (p *Poller) Run(ctx context.Context) {
+	if err := p.scan(); err != nil {
+		log.Printf("activitypub: initial scan failed: %v", err)
+	}
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.scan(); err != nil {
+				log.Printf("activitypub: scan failed: %v", err)
+			}
+		}
+	}
+}
+
+// snapshotKey identifies one chapter/language as announced.
+func snapshotKey(mangaID, chapterNumber, lang string) string {
+	return mangaID + "/" + chapterNumber + "/" + lang
+}
+
+func (p *Poller) scan() error {
+	snapshotPath := filepath.Join(p.Actor.stateDir(), snapshotFile)
+	seen, err := readSnapshot(snapshotPath)
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	manifests, err := scanManifests(p.Actor.DataDir)
+	if err != nil {
+		return fmt.Errorf("scan manifests: %w", err)
+	}
+
+	_, statErr := os.Stat(snapshotPath)
+	firstRun := os.IsNotExist(statErr)
+	changed := false
+
+	for _, m := range manifests {
+		for _, ch := range m.Chapters {
+			for lang := range ch.Languages {
+				key := snapshotKey(m.Manga.ID, ch.Number, lang)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				changed = true
+
+				// Don't spam followers with the entire backlog the first
+				// time the actor ever scans; just record it as the
+				// baseline and start announcing from the next scan.
+				if firstRun {
+					continue
+				}
+
+				if err := p.announce(m.Manga.ID, m.Manga.Title, ch.Number, lang); err != nil {
+					log.Printf("activitypub: failed to announce %s: %v", key, err)
+				}
+			}
+		}
+	}
+
+	if changed || firstRun {
+		if err := writeSnapshot(snapshotPath, seen); err != nil {
+			return fmt.Errorf("write snapshot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Poller) announce(mangaID, mangaTitle, chapterNumber, lang string) error {
+	actor := p.Actor
+	noteID := fmt.Sprintf("%s/ap/notes/%s/%s/%s", "https://"+actor.Domain, mangaID, chapterNumber, lang)
+	pageURL := fmt.Sprintf("https://%s/opds/manga/%s/chapters/%s/%s", actor.Domain, mangaID, chapterNumber, lang)
+	published := time.Now().UTC().Format(time.RFC3339)
+
+	note := map[string]interface{}{
+		"id":           noteID,
+		"type":         "Note",
+		"attributedTo": actor.ID(),
+		"content":      fmt.Sprintf("New chapter %s (%s) of %s is available: %s", chapterNumber, lang, mangaTitle, pageURL),
+		"published":    published,
+		"to":           []string{"https://www.w3.org/ns/activitystreams#Public"},
+	}
+
+	activity := map[string]interface{}{
+		"@context":  "https://www.w3.org/ns/activitystreams",
+		"id":        noteID + "/activity",
+		"type":      "Create",
+		"actor":     actor.ID(),
+		"published": published,
+		"to":        []string{"https://www.w3.org/ns/activitystreams#Public"},
+		"object":    note,
+	}
+
+	store := newOutboxStore(actor.stateDir())
+	if err := store.append(activity); err != nil {
+		return fmt.Errorf("append outbox: %w", err)
+	}
+
+	followers, err := actor.followers.list()
+	if err != nil {
+		return fmt.Errorf("list followers: %w", err)
+	}
+
+	for _, f := range followers {
+		if err := deliver(actor, f.Inbox, activity); err != nil {
+			log.Printf("activitypub: delivery to %s failed: %v", f.Inbox, err)
+		}
+	}
+
+	return nil
+}
+
+func readSnapshot(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]bool{}, nil
+		}
+		return nil, err
+	}
+	seen := map[string]bool{}
+	if err := json.Unmarshal(data, &seen); err != nil {
+		return nil, err
+	}
+	return seen, nil
+}
+
+func writeSnapshot(path string, seen map[string]bool) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(seen, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
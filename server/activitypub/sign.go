@@ -0,0 +1,93 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// deliver signs and POSTs activity to a remote inbox using HTTP Signatures
+// (draft-cavage-http-signatures, as used by Mastodon and other Fediverse
+// servers).
+func deliver(a *Actor, inboxURL string, activity map[string]interface{}) error {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(inboxURL)
+	if err != nil {
+		return fmt.Errorf("parse inbox url: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, inboxURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/activity+json")
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", u.Host)
+	req.Header.Set("Digest", digestHeader(body))
+
+	if err := signRequest(req, a.PrivateKey, a.keyID(), u.Path); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("inbox %s rejected delivery with status %d", inboxURL, resp.StatusCode)
+	}
+	return nil
+}
+
+func digestHeader(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signRequest adds a draft-cavage Signature header covering
+// (request-target), host, date, and digest.
+func signRequest(req *http.Request, key *rsa.PrivateKey, keyID, path string) error {
+	signedHeaders := []string{"(request-target)", "host", "date", "digest"}
+
+	lines := make([]string, 0, len(signedHeaders))
+	for _, h := range signedHeaders {
+		var value string
+		if h == "(request-target)" {
+			value = fmt.Sprintf("post %s", path)
+		} else {
+			value = req.Header.Get(h)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", h, value))
+	}
+	signingString := strings.Join(lines, "\n")
+
+	hashed := sha256.Sum256([]byte(signingString))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+
+	header := fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID,
+		strings.Join(signedHeaders, " "),
+		base64.StdEncoding.EncodeToString(sig),
+	)
+	req.Header.Set("Signature", header)
+	return nil
+}
@@ -0,0 +1,52 @@
+package activitypub
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// safeHTTPClient returns an http.Client whose dialer refuses to connect to
+// loopback, private, link-local, or unspecified addresses. Actor and
+// follower-inbox URLs are taken from unauthenticated request bodies (a
+// Follow/Undo's "actor" field), so without this
+// guard dereferencing them would let a remote party make this server issue
+// requests to internal services (e.g. a cloud metadata endpoint).
+func safeHTTPClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+			if err != nil {
+				return nil, err
+			}
+			var safe net.IP
+			for _, ip := range ips {
+				if isUnsafeIP(ip) {
+					return nil, fmt.Errorf("refusing to dial unsafe address %s", ip)
+				}
+				if safe == nil {
+					safe = ip
+				}
+			}
+			if safe == nil {
+				return nil, fmt.Errorf("no addresses resolved for %s", host)
+			}
+			// Dial the exact IP we just validated rather than addr (the
+			// hostname), so a second, independent resolution inside the
+			// dialer can't be used to rebind past this check.
+			return dialer.DialContext(ctx, network, net.JoinHostPort(safe.String(), port))
+		},
+	}
+	return &http.Client{Timeout: 15 * time.Second, Transport: transport}
+}
+
+func isUnsafeIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
@@ -0,0 +1,50 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type webfingerLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
+type webfingerResponse struct {
+	Subject string          `json:"subject"`
+	Links   []webfingerLink `json:"links"`
+}
+
+// ServeWebfinger handles GET /.well-known/webfinger?resource=acct:manga@domain,
+// resolving the actor's acct URI to its ActivityPub profile.
+func (a *Actor) ServeWebfinger(w http.ResponseWriter, r *http.Request) {
+	resource := r.URL.Query().Get("resource")
+	want := fmt.Sprintf("acct:%s@%s", actorUsername, a.Domain)
+	if resource != want {
+		http.Error(w, "Resource not found", http.StatusNotFound)
+		return
+	}
+
+	resp := webfingerResponse{
+		Subject: want,
+		Links: []webfingerLink{
+			{Rel: "self", Type: "application/activity+json", Href: a.ID()},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/jrd+json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ServeHostMeta handles GET /.well-known/host-meta, the legacy XRD
+// discovery document some Fediverse software still looks up before
+// webfinger.
+func (a *Actor) ServeHostMeta(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/xrd+xml; charset=utf-8")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<XRD xmlns="http://docs.oasis-open.org/ns/xri/xrd-1.0">
+  <Link rel="lrdd" type="application/jrd+json" template="https://%s/.well-known/webfinger?resource={uri}"/>
+</XRD>`, a.Domain)
+}
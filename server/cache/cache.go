@@ -0,0 +1,202 @@
+// Package cache provides a filesystem-backed cache for outbound HTTP
+// fetches (MangaDex API calls, cover downloads, ...) so repeated imports
+// and manifest scans don't keep re-requesting or re-reading the same data.
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDir is Cache's directory when none is configured explicitly.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mangaoff"), nil
+}
+
+// Cache is a filesystem-backed store for outbound fetches, keyed by a
+// sanitized form of the request URL.
+type Cache struct {
+	Dir        string
+	TTL        time.Duration // zero means entries never expire
+	HTTPClient *http.Client
+}
+
+// New returns a Cache rooted at dir with the given TTL.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{Dir: dir, TTL: ttl, HTTPClient: http.DefaultClient}
+}
+
+var (
+	mu      sync.RWMutex
+	enabled = true
+)
+
+// EnableCache turns caching back on after DisableCache. Caching is on by
+// default.
+func EnableCache() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = true
+}
+
+// DisableCache makes Fetch bypass the cache entirely, always hitting the
+// network. Useful for debugging or forcing fresh data.
+func DisableCache() {
+	mu.Lock()
+	defer mu.Unlock()
+	enabled = false
+}
+
+// Enabled reports whether caching is currently turned on.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled
+}
+
+// Fetch returns the body of a GET request to rawURL, served from the cache
+// when a fresh entry exists and written to the cache otherwise. When
+// caching is disabled via DisableCache, it always hits the network.
+func (c *Cache) Fetch(rawURL string) ([]byte, error) {
+	if !Enabled() {
+		return c.get(rawURL)
+	}
+
+	path, err := c.pathFor(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, ok := c.read(path); ok {
+		return data, nil
+	}
+
+	data, err := c.get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create cache dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write cache entry: %w", err)
+	}
+	return data, nil
+}
+
+// Get reads a raw cache entry by key, for callers that want the cache's
+// storage and TTL semantics without going through Fetch's HTTP path (the
+// manifest index, for instance). ok is false on a miss or an expired entry.
+func (c *Cache) Get(key string) (data []byte, ok bool) {
+	return c.read(filepath.Join(c.Dir, sanitizeKey(key)))
+}
+
+// Put writes a raw cache entry by key. See Get.
+func (c *Cache) Put(key string, data []byte) error {
+	path := filepath.Join(c.Dir, sanitizeKey(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Purge removes every entry currently in the cache.
+func (c *Cache) Purge() error {
+	if c.Dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(c.Dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache) read(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if c.TTL > 0 && time.Since(info.ModTime()) > c.TTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *Cache) get(rawURL string) ([]byte, error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, rawURL)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (c *Cache) pathFor(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse url: %w", err)
+	}
+
+	key := u.Path
+	if u.RawQuery != "" {
+		sum := sha1.Sum([]byte(u.RawQuery))
+		key = filepath.Join(key, hex.EncodeToString(sum[:]))
+	}
+
+	return filepath.Join(c.Dir, u.Host, sanitizeKey(key)), nil
+}
+
+// sanitizeKey turns a URL path (or arbitrary key) into a safe relative
+// filesystem path, preserving directory structure but stripping anything
+// that could escape Dir.
+func sanitizeKey(key string) string {
+	parts := strings.Split(key, "/")
+	clean := make([]string, 0, len(parts))
+	for _, p := range parts {
+		switch p {
+		case "", ".", "..":
+			continue
+		}
+		clean = append(clean, p)
+	}
+	if len(clean) == 0 {
+		return "root"
+	}
+	return filepath.Join(clean...)
+}
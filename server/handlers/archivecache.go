@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"archive/zip"
+	"container/list"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// openArchive is a cached *zip.ReadCloser plus its entries in natural
+// reading order, so callers don't have to re-list and re-sort a chapter
+// archive's contents on every page request.
+//
+// refs tracks how many callers currently hold this archive via get; it must
+// only be closed once refs drops to zero, so evictLocked can't yank the
+// reader out from under a request that's still reading from it. Guarded by
+// archiveCache.mu.
+type openArchive struct {
+	reader *zip.ReadCloser
+	mtime  time.Time
+	names  []string // zip entry names in natural reading order
+	refs   int
+	closed bool
+}
+
+// archiveCache is a fixed-size LRU of open chapter archives, keyed by
+// archive path. Entries are invalidated when the underlying file's mtime
+// changes.
+type archiveCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	items    map[string]*list.Element
+}
+
+type archiveCacheEntry struct {
+	path    string
+	archive *openArchive
+}
+
+// newArchiveCache returns an archiveCache holding at most capacity open
+// archives at once.
+func newArchiveCache(capacity int) *archiveCache {
+	return &archiveCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get returns the open archive for path, opening and sorting it if it isn't
+// already cached or if the file has changed since it was cached. The
+// returned archive is pinned against eviction-triggered closing until the
+// caller calls release; every get must be paired with exactly one release.
+func (c *archiveCache) get(path string) (*openArchive, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if archive, ok := c.lookupLocked(path, info.ModTime()); ok {
+		c.mu.Unlock()
+		return archive, nil
+	}
+	c.mu.Unlock()
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive %s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(reader.File))
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		names = append(names, f.Name)
+	}
+	sort.Slice(names, func(i, j int) bool { return naturalLess(names[i], names[j]) })
+
+	archive := &openArchive{reader: reader, mtime: info.ModTime(), names: names, refs: 1}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another goroutine may have opened and cached path while we were
+	// opening our own reader above (two concurrent cold misses on the same
+	// path). If so, adopt its entry and close ours instead of pushing a
+	// second node for the same key, which would desync order from items and
+	// let evictLocked delete an unrelated live entry.
+	if winner, ok := c.lookupLocked(path, info.ModTime()); ok {
+		reader.Close()
+		return winner, nil
+	}
+
+	el := c.order.PushFront(&archiveCacheEntry{path: path, archive: archive})
+	c.items[path] = el
+	c.evictLocked()
+
+	return archive, nil
+}
+
+// lookupLocked returns the cached archive for path if one exists and its
+// mtime still matches, bumping it to the front of the LRU order and pinning
+// it with a reference. If a stale entry is found at path instead, it's
+// evicted (closed once unreferenced) so the caller can replace it. Callers
+// must hold c.mu.
+func (c *archiveCache) lookupLocked(path string, mtime time.Time) (*openArchive, bool) {
+	el, ok := c.items[path]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*archiveCacheEntry)
+	if entry.archive.mtime.Equal(mtime) {
+		c.order.MoveToFront(el)
+		entry.archive.refs++
+		return entry.archive, true
+	}
+
+	// Stale: drop it so the caller reopens.
+	c.order.Remove(el)
+	delete(c.items, path)
+	c.closeOrDeferLocked(entry.archive)
+	return nil, false
+}
+
+// release drops a reference obtained from get. Once an archive has been
+// evicted or invalidated and its last reference is released, its reader is
+// closed.
+func (c *archiveCache) release(archive *openArchive) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	archive.refs--
+	if archive.refs <= 0 && archive.closed {
+		archive.reader.Close()
+	}
+}
+
+// closeOrDeferLocked closes archive's reader immediately if nothing holds a
+// reference to it, or marks it for closing once its last reference is
+// released. Callers must hold c.mu.
+func (c *archiveCache) closeOrDeferLocked(archive *openArchive) {
+	if archive.refs <= 0 {
+		archive.reader.Close()
+		return
+	}
+	archive.closed = true
+}
+
+func (c *archiveCache) evictLocked() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil {
+			return
+		}
+		entry := oldest.Value.(*archiveCacheEntry)
+		c.order.Remove(oldest)
+		delete(c.items, entry.path)
+		c.closeOrDeferLocked(entry.archive)
+	}
+}
+
+// naturalLess compares two strings so that embedded numbers sort by value
+// rather than lexicographically (page "2.jpg" before page "10.jpg").
+func naturalLess(a, b string) bool {
+	ar, br := []rune(a), []rune(b)
+	i, j := 0, 0
+	for i < len(ar) && j < len(br) {
+		ca, cb := ar[i], br[j]
+		if unicode.IsDigit(ca) && unicode.IsDigit(cb) {
+			starta, startb := i, j
+			for i < len(ar) && unicode.IsDigit(ar[i]) {
+				i++
+			}
+			for j < len(br) && unicode.IsDigit(br[j]) {
+				j++
+			}
+			na := strings.TrimLeft(string(ar[starta:i]), "0")
+			nb := strings.TrimLeft(string(br[startb:j]), "0")
+			if len(na) != len(nb) {
+				return len(na) < len(nb)
+			}
+			if na != nb {
+				return na < nb
+			}
+			continue
+		}
+		if ca != cb {
+			return ca < cb
+		}
+		i++
+		j++
+	}
+	return len(ar)-i < len(br)-j
+}
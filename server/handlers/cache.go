@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/smoreg/mangaoff-server/cache"
+)
+
+// CacheHandler exposes admin operations over the on-disk fetch cache.
+type CacheHandler struct {
+	Cache *cache.Cache
+}
+
+// NewCacheHandler returns a CacheHandler backed by c.
+func NewCacheHandler(c *cache.Cache) *CacheHandler {
+	return &CacheHandler{Cache: c}
+}
+
+// Purge handles POST /api/v1/cache/purge, clearing every cached entry.
+func (h *CacheHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if err := h.Cache.Purge(); err != nil {
+		http.Error(w, "Purge failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
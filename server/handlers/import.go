@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MangaIDPattern matches a MangaDex manga UUID. Import writes to a
+// filesystem path built from the ID (DataDir/<id>/...), so anything not
+// shaped like a UUID is rejected before it reaches the importer. Exported so
+// importer.Importer can reuse the same pattern rather than keeping its own
+// copy in sync by hand.
+var MangaIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Importer mirrors a single manga from an upstream source into DataDir. It
+// is implemented by importer.Importer; the interface lives here so
+// handlers doesn't import the importer package and create a cycle.
+type Importer interface {
+	Import(mangaID string, opts ImportOptions) error
+}
+
+// ImportOptions carries the per-import settings the handler passes to
+// Importer.Import.
+type ImportOptions struct {
+	Languages []string
+}
+
+// ImportHandler exposes an admin endpoint that triggers a MangaDex import.
+type ImportHandler struct {
+	Importer Importer
+}
+
+// NewImportHandler returns an ImportHandler backed by the given Importer.
+func NewImportHandler(imp Importer) *ImportHandler {
+	return &ImportHandler{Importer: imp}
+}
+
+type importRequest struct {
+	Languages []string `json:"languages"`
+}
+
+type importResponse struct {
+	Status  string `json:"status"`
+	MangaID string `json:"manga_id"`
+}
+
+// ImportMangaDex handles POST /api/v1/import/mangadex/{id}. It runs the
+// import synchronously and reports success or failure once complete.
+func (h *ImportHandler) ImportMangaDex(w http.ResponseWriter, r *http.Request) {
+	mangaID := chi.URLParam(r, "id")
+	if !MangaIDPattern.MatchString(mangaID) {
+		http.Error(w, "Manga ID must be a MangaDex UUID", http.StatusBadRequest)
+		return
+	}
+
+	var req importRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.Importer.Import(mangaID, ImportOptions{Languages: req.Languages}); err != nil {
+		log.Printf("import %s failed: %v", mangaID, err)
+		http.Error(w, "Import failed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(importResponse{Status: "ok", MangaID: mangaID})
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// indexEntry is the data ListManga needs about one manga, kept in memory so
+// a request doesn't have to re-read and re-parse every manifest.json on
+// disk.
+type indexEntry struct {
+	ID              string
+	Title           string
+	NormalizedTitle string
+	Cover           string
+	ChapterCount    int
+	LangCounts      map[string]int // language code -> chapters available in it
+	UpdatedAt       time.Time      // manifest.json's mtime
+}
+
+// MangaIndex is an in-memory, fsnotify-maintained index of every manga
+// manifest under DataDir, so ListManga's per-request cost is O(matches)
+// rather than O(all manifests on disk).
+type MangaIndex struct {
+	DataDir string
+
+	mu      sync.RWMutex
+	entries map[string]*indexEntry
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewMangaIndex builds an index of DataDir and starts an fsnotify watcher
+// that keeps it current as manga are added, updated, or removed.
+func NewMangaIndex(dataDir string) (*MangaIndex, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &MangaIndex{
+		DataDir: dataDir,
+		entries: make(map[string]*indexEntry),
+		watcher: watcher,
+		done:    make(chan struct{}),
+	}
+
+	if err := watcher.Add(dataDir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	if err := idx.rebuild(); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go idx.watch()
+
+	return idx, nil
+}
+
+// Close stops the watcher goroutine.
+func (idx *MangaIndex) Close() error {
+	close(idx.done)
+	return idx.watcher.Close()
+}
+
+// rebuild scans DataDir from scratch, watching each manga subdirectory so
+// its manifest.json changes are picked up individually.
+func (idx *MangaIndex) rebuild() error {
+	entries, err := os.ReadDir(idx.DataDir)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.entries = make(map[string]*indexEntry)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if err := idx.watcher.Add(filepath.Join(idx.DataDir, e.Name())); err != nil {
+			log.Printf("manga index: failed to watch %s: %v", e.Name(), err)
+		}
+		idx.refreshLocked(e.Name())
+	}
+	return nil
+}
+
+// refreshLocked (re)loads mangaID's manifest and updates its index entry.
+// Callers must hold idx.mu.
+func (idx *MangaIndex) refreshLocked(mangaID string) {
+	manifestPath := filepath.Join(idx.DataDir, mangaID, "manifest.json")
+	info, err := os.Stat(manifestPath)
+	if err != nil {
+		delete(idx.entries, mangaID)
+		return
+	}
+
+	manifest, err := loadManifestFile(manifestPath)
+	if err != nil {
+		delete(idx.entries, mangaID)
+		return
+	}
+
+	langCounts := map[string]int{}
+	for _, ch := range manifest.Chapters {
+		for lang := range ch.Languages {
+			langCounts[lang]++
+		}
+	}
+
+	idx.entries[mangaID] = &indexEntry{
+		ID:              manifest.Manga.ID,
+		Title:           manifest.Manga.Title,
+		NormalizedTitle: normalizeTitle(manifest.Manga.Title),
+		Cover:           manifest.Manga.Cover,
+		ChapterCount:    len(manifest.Chapters),
+		LangCounts:      langCounts,
+		UpdatedAt:       info.ModTime(),
+	}
+}
+
+// watch processes fsnotify events until Close is called.
+func (idx *MangaIndex) watch() {
+	for {
+		select {
+		case <-idx.done:
+			return
+		case event, ok := <-idx.watcher.Events:
+			if !ok {
+				return
+			}
+			idx.handleEvent(event)
+		case err, ok := <-idx.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("manga index: watcher error: %v", err)
+		}
+	}
+}
+
+func (idx *MangaIndex) handleEvent(event fsnotify.Event) {
+	rel, err := filepath.Rel(idx.DataDir, event.Name)
+	if err != nil || rel == "." {
+		return
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	mangaID := parts[0]
+
+	switch {
+	case len(parts) == 1 && event.Op&(fsnotify.Create) != 0:
+		// A new manga directory appeared directly under DataDir.
+		if err := idx.watcher.Add(event.Name); err != nil {
+			log.Printf("manga index: failed to watch %s: %v", event.Name, err)
+		}
+		idx.mu.Lock()
+		idx.refreshLocked(mangaID)
+		idx.mu.Unlock()
+
+	case len(parts) == 1 && event.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		idx.mu.Lock()
+		delete(idx.entries, mangaID)
+		idx.mu.Unlock()
+
+	case len(parts) == 2 && parts[1] == "manifest.json":
+		idx.mu.Lock()
+		idx.refreshLocked(mangaID)
+		idx.mu.Unlock()
+	}
+}
+
+// maxListLimit bounds ListQuery.Limit so one request can't force a
+// full-index response.
+const maxListLimit = 100
+
+// ListQuery controls ListManga's search, filtering, pagination, and sort.
+type ListQuery struct {
+	Query string // case/diacritic-insensitive substring or prefix match against title
+	Lang  string // restrict to manga with at least one chapter in this language
+	Page  int    // 1-based
+	Limit int
+	Sort  string // "title" (default), "chapters", or "updated"
+}
+
+// List returns the page of manga matching q, the total number of matches
+// (before pagination), and the effective page/limit used.
+func (idx *MangaIndex) List(q ListQuery) ([]MangaListItem, int, int, int) {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit <= 0 {
+		q.Limit = 20
+	}
+	if q.Limit > maxListLimit {
+		q.Limit = maxListLimit
+	}
+
+	needle := normalizeTitle(q.Query)
+
+	idx.mu.RLock()
+	matches := make([]*indexEntry, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		if q.Lang != "" && e.LangCounts[q.Lang] == 0 {
+			continue
+		}
+		if needle != "" && !strings.Contains(e.NormalizedTitle, needle) {
+			continue
+		}
+		matches = append(matches, e)
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		switch q.Sort {
+		case "chapters":
+			if matches[i].ChapterCount != matches[j].ChapterCount {
+				return matches[i].ChapterCount > matches[j].ChapterCount
+			}
+		case "updated":
+			if !matches[i].UpdatedAt.Equal(matches[j].UpdatedAt) {
+				return matches[i].UpdatedAt.After(matches[j].UpdatedAt)
+			}
+		}
+		return matches[i].NormalizedTitle < matches[j].NormalizedTitle
+	})
+
+	total := len(matches)
+	start := (q.Page - 1) * q.Limit
+	if start > total {
+		start = total
+	}
+	end := start + q.Limit
+	if end > total {
+		end = total
+	}
+
+	items := make([]MangaListItem, 0, end-start)
+	for _, e := range matches[start:end] {
+		chapterCount := e.ChapterCount
+		if q.Lang != "" {
+			chapterCount = e.LangCounts[q.Lang]
+		}
+		items = append(items, MangaListItem{
+			ID:           e.ID,
+			Title:        e.Title,
+			Cover:        e.Cover,
+			ChapterCount: chapterCount,
+		})
+	}
+
+	return items, total, q.Page, q.Limit
+}
+
+// All returns every indexed manga sorted by title, bypassing the
+// maxListLimit page-size cap — for callers like the OPDS feed that need a
+// complete catalog rather than one page of search results.
+func (idx *MangaIndex) All() []MangaListItem {
+	idx.mu.RLock()
+	matches := make([]*indexEntry, 0, len(idx.entries))
+	for _, e := range idx.entries {
+		matches = append(matches, e)
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].NormalizedTitle < matches[j].NormalizedTitle })
+
+	items := make([]MangaListItem, 0, len(matches))
+	for _, e := range matches {
+		items = append(items, MangaListItem{
+			ID:           e.ID,
+			Title:        e.Title,
+			Cover:        e.Cover,
+			ChapterCount: e.ChapterCount,
+		})
+	}
+	return items
+}
+
+// loadManifestFile reads and parses a manifest.json straight off disk,
+// bypassing the cache.Cache that fronts MangaHandler's manifest reads — the
+// index keeps its own
+// in-memory copy of what it needs instead.
+func loadManifestFile(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
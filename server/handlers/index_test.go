@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func testIndex() *MangaIndex {
+	return &MangaIndex{
+		entries: map[string]*indexEntry{
+			"1": {
+				ID: "1", Title: "Alpha Strike", NormalizedTitle: normalizeTitle("Alpha Strike"),
+				ChapterCount: 5, LangCounts: map[string]int{"en": 5, "ja": 2},
+				UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			"2": {
+				ID: "2", Title: "Bravo Tales", NormalizedTitle: normalizeTitle("Bravo Tales"),
+				ChapterCount: 20, LangCounts: map[string]int{"en": 20},
+				UpdatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			"3": {
+				ID: "3", Title: "Café Chronicles", NormalizedTitle: normalizeTitle("Café Chronicles"),
+				ChapterCount: 1, LangCounts: map[string]int{"fr": 1},
+				UpdatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestMangaIndexListDefaults(t *testing.T) {
+	idx := testIndex()
+
+	items, total, page, limit := idx.List(ListQuery{})
+	if total != 3 || page != 1 || limit != 20 {
+		t.Fatalf("got total=%d page=%d limit=%d, want 3/1/20", total, page, limit)
+	}
+	if len(items) != 3 {
+		t.Fatalf("got %d items, want 3", len(items))
+	}
+	// Default sort is by (normalized) title.
+	want := []string{"Alpha Strike", "Bravo Tales", "Café Chronicles"}
+	for i, w := range want {
+		if items[i].Title != w {
+			t.Errorf("item %d = %q, want %q", i, items[i].Title, w)
+		}
+	}
+}
+
+func TestMangaIndexListQueryFilter(t *testing.T) {
+	idx := testIndex()
+
+	// Diacritic/case-insensitive substring match.
+	items, total, _, _ := idx.List(ListQuery{Query: "cafe chronicles"})
+	if total != 1 || len(items) != 1 || items[0].ID != "3" {
+		t.Fatalf("query filter returned %+v, want only manga 3", items)
+	}
+}
+
+func TestMangaIndexListLangFilter(t *testing.T) {
+	idx := testIndex()
+
+	items, total, _, _ := idx.List(ListQuery{Lang: "ja"})
+	if total != 1 || len(items) != 1 || items[0].ID != "1" {
+		t.Fatalf("lang filter returned %+v, want only manga 1", items)
+	}
+	// ChapterCount should reflect the language-specific count, not the total.
+	if items[0].ChapterCount != 2 {
+		t.Errorf("ChapterCount = %d, want 2 (ja-only count)", items[0].ChapterCount)
+	}
+}
+
+func TestMangaIndexListSort(t *testing.T) {
+	idx := testIndex()
+
+	items, _, _, _ := idx.List(ListQuery{Sort: "chapters"})
+	want := []string{"Bravo Tales", "Alpha Strike", "Café Chronicles"}
+	for i, w := range want {
+		if items[i].Title != w {
+			t.Errorf("sort=chapters item %d = %q, want %q", i, items[i].Title, w)
+		}
+	}
+
+	items, _, _, _ = idx.List(ListQuery{Sort: "updated"})
+	want = []string{"Bravo Tales", "Café Chronicles", "Alpha Strike"}
+	for i, w := range want {
+		if items[i].Title != w {
+			t.Errorf("sort=updated item %d = %q, want %q", i, items[i].Title, w)
+		}
+	}
+}
+
+func TestMangaIndexListPagination(t *testing.T) {
+	idx := testIndex()
+
+	items, total, page, limit := idx.List(ListQuery{Page: 2, Limit: 2})
+	if total != 3 || page != 2 || limit != 2 {
+		t.Fatalf("got total=%d page=%d limit=%d, want 3/2/2", total, page, limit)
+	}
+	if len(items) != 1 {
+		t.Fatalf("got %d items on page 2, want 1", len(items))
+	}
+	if items[0].Title != "Café Chronicles" {
+		t.Errorf("page 2 item = %q, want %q", items[0].Title, "Café Chronicles")
+	}
+}
+
+func TestMangaIndexListLimitCapped(t *testing.T) {
+	idx := testIndex()
+
+	_, _, _, limit := idx.List(ListQuery{Limit: 1000})
+	if limit != maxListLimit {
+		t.Errorf("limit = %d, want capped to maxListLimit (%d)", limit, maxListLimit)
+	}
+}
+
+func TestMangaIndexAllIgnoresLimit(t *testing.T) {
+	idx := testIndex()
+
+	items := idx.All()
+	if len(items) != 3 {
+		t.Fatalf("All() returned %d items, want 3", len(items))
+	}
+	if items[0].Title != "Alpha Strike" {
+		t.Errorf("All()[0] = %q, want sorted by title first entry %q", items[0].Title, "Alpha Strike")
+	}
+}
@@ -5,12 +5,21 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/smoreg/mangaoff-server/cache"
 )
 
 type MangaHandler struct {
 	DataDir string
+	// Cache, if set, fronts manifest.json reads so GetManga doesn't
+	// re-read every manifest on disk on each request.
+	Cache *cache.Cache
+	// Index backs ListManga's search, filtering, pagination, and sort. It
+	// must be set; see NewMangaIndex.
+	Index *MangaIndex
 }
 
 type MangaListItem struct {
@@ -52,38 +61,44 @@ func NewMangaHandler(dataDir string) *MangaHandler {
 	return &MangaHandler{DataDir: dataDir}
 }
 
-// ListManga returns all available manga
+// MangaListResponse is ListManga's paginated response envelope.
+type MangaListResponse struct {
+	Items []MangaListItem `json:"items"`
+	Total int             `json:"total"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+}
+
+// ListManga returns manga matching the query, filtering, and sort
+// parameters, served from the in-memory index rather than re-reading every
+// manifest.json on disk:
+//
+//	q      substring/prefix match against title (case/diacritic-insensitive)
+//	lang   restrict to manga with at least one chapter in this language
+//	page   1-based page number (default 1)
+//	limit  page size (default 20)
+//	sort   "title" (default), "chapters", or "updated"
 func (h *MangaHandler) ListManga(w http.ResponseWriter, r *http.Request) {
-	var mangaList []MangaListItem
+	query := r.URL.Query()
 
-	// Scan data directory for manga folders with manifest.json
-	entries, err := os.ReadDir(h.DataDir)
-	if err != nil {
-		http.Error(w, "Failed to read data directory", http.StatusInternalServerError)
-		return
-	}
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		manifestPath := filepath.Join(h.DataDir, entry.Name(), "manifest.json")
-		manifest, err := loadManifest(manifestPath)
-		if err != nil {
-			continue
-		}
-
-		mangaList = append(mangaList, MangaListItem{
-			ID:           manifest.Manga.ID,
-			Title:        manifest.Manga.Title,
-			Cover:        manifest.Manga.Cover,
-			ChapterCount: len(manifest.Chapters),
-		})
-	}
+	items, total, page, limit := h.Index.List(ListQuery{
+		Query: query.Get("q"),
+		Lang:  query.Get("lang"),
+		Page:  page,
+		Limit: limit,
+		Sort:  query.Get("sort"),
+	})
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(mangaList)
+	json.NewEncoder(w).Encode(MangaListResponse{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	})
 }
 
 // GetManga returns manga details with chapters
@@ -95,7 +110,7 @@ func (h *MangaHandler) GetManga(w http.ResponseWriter, r *http.Request) {
 	}
 
 	manifestPath := filepath.Join(h.DataDir, mangaID, "manifest.json")
-	manifest, err := loadManifest(manifestPath)
+	manifest, err := h.loadManifest(manifestPath)
 	if err != nil {
 		http.Error(w, "Manga not found", http.StatusNotFound)
 		return
@@ -110,10 +125,29 @@ func (h *MangaHandler) GetManga(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func loadManifest(path string) (*Manifest, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// loadManifest reads and parses a manifest.json, going through h.Cache when
+// one is configured so repeated requests don't re-read unchanged files.
+func (h *MangaHandler) loadManifest(path string) (*Manifest, error) {
+	var data []byte
+	if h.Cache != nil {
+		if cached, ok := h.Cache.Get(path); ok {
+			data = cached
+		} else {
+			fresh, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := h.Cache.Put(path, fresh); err != nil {
+				return nil, err
+			}
+			data = fresh
+		}
+	} else {
+		fresh, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		data = fresh
 	}
 
 	var manifest Manifest
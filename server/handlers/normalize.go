@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldDiacritics strips combining marks after NFD decomposition, so
+// normalizeTitle can match "café" against a search for "cafe".
+var foldDiacritics = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// normalizeTitle lowercases s and strips diacritics, producing a form
+// suitable for case/diacritic-insensitive substring and prefix matching.
+func normalizeTitle(s string) string {
+	folded, _, err := transform.String(foldDiacritics, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(strings.TrimSpace(folded))
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import "testing"
+
+func TestNormalizeTitle(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"Café", "cafe"},
+		{"  Trim Me  ", "trim me"},
+		{"ALL CAPS", "all caps"},
+		{"Pokémon Adventures", "pokemon adventures"},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		if got := normalizeTitle(c.in); got != c.want {
+			t.Errorf("normalizeTitle(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultArchiveCacheSize bounds how many chapter archives are kept open at
+// once across all requests.
+const defaultArchiveCacheSize = 32
+
+var globalArchiveCache = newArchiveCache(defaultArchiveCacheSize)
+
+type pageListResponse struct {
+	Count int `json:"count"`
+}
+
+// ListPages handles GET /api/v1/manga/{id}/chapters/{num}/{lang}/pages,
+// returning the page count for a chapter/language so clients know the
+// valid range for GetPage.
+func (h *MangaHandler) ListPages(w http.ResponseWriter, r *http.Request) {
+	archivePath, _, err := h.resolveArchive(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	archive, err := globalArchiveCache.get(archivePath)
+	if err != nil {
+		http.Error(w, "Failed to open archive", http.StatusInternalServerError)
+		return
+	}
+	defer globalArchiveCache.release(archive)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pageListResponse{Count: len(archive.names)})
+}
+
+// GetPage handles GET /api/v1/manga/{id}/chapters/{num}/{lang}/pages/{n},
+// streaming the n'th page (1-indexed, in natural archive order) with
+// content sniffing, ETag, and Range support.
+func (h *MangaHandler) GetPage(w http.ResponseWriter, r *http.Request) {
+	archivePath, _, err := h.resolveArchive(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	n, err := strconv.Atoi(chi.URLParam(r, "n"))
+	if err != nil || n < 1 {
+		http.Error(w, "Invalid page number", http.StatusBadRequest)
+		return
+	}
+
+	archive, err := globalArchiveCache.get(archivePath)
+	if err != nil {
+		http.Error(w, "Failed to open archive", http.StatusInternalServerError)
+		return
+	}
+	defer globalArchiveCache.release(archive)
+
+	if n > len(archive.names) {
+		http.Error(w, "Page not found", http.StatusNotFound)
+		return
+	}
+	name := archive.names[n-1]
+
+	var zf *zip.File
+	for _, f := range archive.reader.File {
+		if f.Name == name {
+			zf = f
+			break
+		}
+	}
+	if zf == nil {
+		http.Error(w, "Page not found", http.StatusInternalServerError)
+		return
+	}
+
+	rc, err := zf.Open()
+	if err != nil {
+		http.Error(w, "Failed to read page", http.StatusInternalServerError)
+		return
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		http.Error(w, "Failed to read page", http.StatusInternalServerError)
+		return
+	}
+
+	etag := pageETag(archivePath, name, int64(len(data)))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+
+	http.ServeContent(w, r, name, archive.mtime, bytes.NewReader(data))
+}
+
+// resolveArchive looks up the archive path and LanguageInfo for the
+// manga/chapter/language named in the request's URL params.
+func (h *MangaHandler) resolveArchive(r *http.Request) (string, *LanguageInfo, error) {
+	mangaID := chi.URLParam(r, "id")
+	number := chi.URLParam(r, "num")
+	lang := chi.URLParam(r, "lang")
+
+	manifestPath := filepath.Join(h.DataDir, mangaID, "manifest.json")
+	manifest, err := h.loadManifest(manifestPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("manga not found")
+	}
+
+	for _, ch := range manifest.Chapters {
+		if ch.Number != number {
+			continue
+		}
+		info, ok := ch.Languages[lang]
+		if !ok {
+			return "", nil, fmt.Errorf("language not found")
+		}
+		return filepath.Join(h.DataDir, mangaID, info.Archive), &info, nil
+	}
+
+	return "", nil, fmt.Errorf("chapter not found")
+}
+
+func pageETag(archivePath, entryName string, size int64) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s:%s:%d", archivePath, entryName, size)))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
@@ -0,0 +1,344 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/smoreg/mangaoff-server/cache"
+	"github.com/smoreg/mangaoff-server/handlers"
+)
+
+// Importer fetches a manga from MangaDex and writes it into DataDir using
+// the on-disk layout handlers.Manifest expects.
+type Importer struct {
+	Client  *Client
+	DataDir string
+
+	// Cache, if set, is overwritten alongside manifest.json on every write
+	// so MangaHandler.loadManifest (which fronts reads with the same cache)
+	// doesn't keep serving a pre-import manifest until its TTL expires.
+	Cache *cache.Cache
+
+	// Concurrency bounds how many page images are downloaded at once per
+	// chapter. Defaults to 4.
+	Concurrency int
+	// MaxRetries bounds retry attempts for a single HTTP request. Defaults
+	// to 3.
+	MaxRetries int
+}
+
+// New returns an Importer that writes into dataDir using client.
+func New(client *Client, dataDir string) *Importer {
+	return &Importer{Client: client, DataDir: dataDir, Concurrency: 4, MaxRetries: 3}
+}
+
+// Import downloads mangaID's metadata, chapter list, and page images, and
+// merges the result into DataDir/<mangaID>/manifest.json. Chapters/languages
+// already present in the manifest are skipped, so re-running Import against
+// an existing directory only fetches what's missing. It satisfies
+// handlers.Importer.
+func (im *Importer) Import(mangaID string, opts handlers.ImportOptions) error {
+	if !handlers.MangaIDPattern.MatchString(mangaID) {
+		return fmt.Errorf("invalid manga ID %q: must be a MangaDex UUID", mangaID)
+	}
+
+	if im.Concurrency <= 0 {
+		im.Concurrency = 4
+	}
+	if im.MaxRetries <= 0 {
+		im.MaxRetries = 3
+	}
+
+	mangaDir := filepath.Join(im.DataDir, mangaID)
+	if err := os.MkdirAll(mangaDir, 0o755); err != nil {
+		return fmt.Errorf("create manga dir: %w", err)
+	}
+
+	manga, err := im.Client.GetManga(mangaID)
+	if err != nil {
+		return err
+	}
+
+	manifestPath := filepath.Join(mangaDir, "manifest.json")
+	manifest := loadOrInitManifest(manifestPath, mangaID, bestTitle(manga.Title))
+
+	if manifest.Manga.Cover == "" {
+		if cover, err := im.downloadCover(manga, mangaDir, im.MaxRetries); err != nil {
+			log.Printf("importer: cover download failed for %s: %v", mangaID, err)
+		} else {
+			manifest.Manga.Cover = cover
+		}
+	}
+
+	chapters, err := im.allChapters(mangaID)
+	if err != nil {
+		return err
+	}
+
+	for _, ch := range chapters {
+		if !wantLanguage(ch.Language, opts.Languages) {
+			continue
+		}
+
+		chapter := findOrAddChapter(manifest, ch)
+		if _, ok := chapter.Languages[ch.Language]; ok {
+			continue // idempotent: already imported
+		}
+
+		info, err := im.importChapter(ch, mangaDir)
+		if err != nil {
+			log.Printf("importer: chapter %s (%s) failed: %v", ch.Number, ch.Language, err)
+			continue
+		}
+
+		if chapter.Languages == nil {
+			chapter.Languages = map[string]handlers.LanguageInfo{}
+		}
+		chapter.Languages[ch.Language] = *info
+
+		if err := im.writeManifest(manifestPath, manifest); err != nil {
+			return fmt.Errorf("write manifest: %w", err)
+		}
+	}
+
+	return im.writeManifest(manifestPath, manifest)
+}
+
+func (im *Importer) allChapters(mangaID string) ([]Chapter, error) {
+	var all []Chapter
+	for page := 0; ; page++ {
+		batch, err := im.Client.GetChapters(mangaID, GetChaptersParams{Page: page, Limit: 100})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, batch...)
+		if len(batch) < 100 {
+			break
+		}
+	}
+	return all, nil
+}
+
+// importChapter downloads every page of ch, packs them into a per-chapter
+// CBZ archive, and returns the resulting LanguageInfo.
+func (im *Importer) importChapter(ch Chapter, mangaDir string) (*handlers.LanguageInfo, error) {
+	pages, err := withRetry(im.MaxRetries, func() (*ChapterPages, error) {
+		return im.Client.GetChapter(ch.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	archiveName := fmt.Sprintf("%s-%s.cbz", sanitize(ch.Number), ch.Language)
+	archivePath := filepath.Join(mangaDir, archiveName)
+
+	pageData := make([][]byte, len(pages.Chapter.Data))
+	sem := make(chan struct{}, im.Concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i := range pages.Chapter.Data {
+		i := i
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			data, err := withRetry(im.MaxRetries, func() ([]byte, error) {
+				return im.Client.Download(pages.PageURL(i))
+			})
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			pageData[i] = data
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := writeCBZ(archivePath, pages.Chapter.Data, pageData); err != nil {
+		return nil, err
+	}
+
+	return &handlers.LanguageInfo{Archive: archiveName, PageCount: len(pageData)}, nil
+}
+
+func (im *Importer) downloadCover(manga *Manga, mangaDir string, maxRetries int) (string, error) {
+	covers, err := im.Client.GetCovers(manga.ID)
+	if err != nil {
+		return "", err
+	}
+	if manga.CoverArtID == "" && len(covers) == 0 {
+		return "", fmt.Errorf("no cover art available")
+	}
+
+	var fileName string
+	for _, c := range covers {
+		if c.ID == manga.CoverArtID {
+			fileName = c.FileName
+			break
+		}
+	}
+	if fileName == "" && len(covers) > 0 {
+		fileName = covers[0].FileName
+	}
+	if fileName == "" {
+		return "", fmt.Errorf("no cover art available")
+	}
+
+	url := fmt.Sprintf("https://uploads.mangadex.org/covers/%s/%s", manga.ID, fileName)
+	data, err := withRetry(maxRetries, func() ([]byte, error) { return im.Client.Download(url) })
+	if err != nil {
+		return "", err
+	}
+
+	coverPath := filepath.Join(mangaDir, "cover"+filepath.Ext(fileName))
+	if err := os.WriteFile(coverPath, data, 0o644); err != nil {
+		return "", err
+	}
+	return filepath.Base(coverPath), nil
+}
+
+func writeCBZ(path string, names []string, data [][]byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for i, name := range names {
+		w, err := zw.Create(fmt.Sprintf("%04d%s", i+1, filepath.Ext(name)))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data[i]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// withRetry calls fn up to maxRetries+1 times with exponential backoff and
+// jitter, returning the first successful result.
+func withRetry[T any](maxRetries int, fn func() (T, error)) (T, error) {
+	var result T
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		result, err = fn()
+		if err == nil {
+			return result, nil
+		}
+		if attempt == maxRetries {
+			break
+		}
+		backoff := time.Duration(1<<attempt) * 200 * time.Millisecond
+		backoff += time.Duration(rand.Intn(100)) * time.Millisecond
+		time.Sleep(backoff)
+	}
+	return result, err
+}
+
+func loadOrInitManifest(path, id, title string) *handlers.Manifest {
+	if data, err := os.ReadFile(path); err == nil {
+		var m handlers.Manifest
+		if json.Unmarshal(data, &m) == nil {
+			return &m
+		}
+	}
+	return &handlers.Manifest{
+		Version: 1,
+		Manga:   handlers.MangaInfo{ID: id, Title: title},
+	}
+}
+
+// writeManifest writes m to path and, if im.Cache is set, overwrites the
+// cache entry keyed by path so readers fronted by the same cache (see
+// handlers.MangaHandler.loadManifest) see the update immediately instead of
+// serving a stale copy until the cache entry's TTL expires.
+func (im *Importer) writeManifest(path string, m *handlers.Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	if im.Cache != nil {
+		if err := im.Cache.Put(path, data); err != nil {
+			return fmt.Errorf("update manifest cache: %w", err)
+		}
+	}
+	return nil
+}
+
+func findOrAddChapter(m *handlers.Manifest, ch Chapter) *handlers.Chapter {
+	for i := range m.Chapters {
+		if m.Chapters[i].Number == ch.Number {
+			return &m.Chapters[i]
+		}
+	}
+	m.Chapters = append(m.Chapters, handlers.Chapter{
+		Number:    ch.Number,
+		Title:     ch.Title,
+		Languages: map[string]handlers.LanguageInfo{},
+	})
+	return &m.Chapters[len(m.Chapters)-1]
+}
+
+func wantLanguage(lang string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func bestTitle(titles map[string]string) string {
+	for _, lang := range []string{"en", "ja-ro", "ja"} {
+		if t, ok := titles[lang]; ok {
+			return t
+		}
+	}
+	for _, t := range titles {
+		return t
+	}
+	return ""
+}
+
+func sanitize(s string) string {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '.', r == '-':
+			out = append(out, r)
+		default:
+			out = append(out, '_')
+		}
+	}
+	if len(out) == 0 {
+		return "0"
+	}
+	return string(out)
+}
@@ -0,0 +1,227 @@
+// Package importer fetches manga metadata, chapters, and page images from
+// the MangaDex API and materializes them on disk in the layout that
+// handlers.Manifest expects.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/smoreg/mangaoff-server/cache"
+)
+
+const defaultBaseURL = "https://api.mangadex.org"
+
+// Client is a minimal MangaDex API client covering the endpoints needed to
+// mirror a manga's metadata, chapters, and cover art locally.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	// Cache, if set, fronts every request made by the client so repeated
+	// imports don't re-fetch metadata or page images that haven't changed.
+	Cache *cache.Cache
+}
+
+// NewClient returns a Client pointed at the public MangaDex API.
+func NewClient() *Client {
+	return &Client{
+		BaseURL:    defaultBaseURL,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Manga is the subset of MangaDex manga attributes we care about.
+type Manga struct {
+	ID         string            `json:"id"`
+	Title      map[string]string `json:"title"`
+	CoverArtID string            `json:"-"`
+}
+
+// Cover is a MangaDex cover_art entry.
+type Cover struct {
+	ID       string `json:"id"`
+	FileName string `json:"fileName"`
+	Locale   string `json:"locale"`
+}
+
+// Chapter is the subset of MangaDex chapter attributes needed to download
+// and name its pages.
+type Chapter struct {
+	ID       string `json:"id"`
+	Number   string `json:"chapter"`
+	Title    string `json:"title"`
+	Language string `json:"translatedLanguage"`
+}
+
+// ChapterPages is the MangaDex "at-home" server response used to build page
+// image URLs for a chapter.
+type ChapterPages struct {
+	BaseURL string `json:"baseUrl"`
+	Chapter struct {
+		Hash string   `json:"hash"`
+		Data []string `json:"data"`
+	} `json:"chapter"`
+}
+
+// GetChaptersParams controls pagination and language filtering of
+// GetChapters, mirroring the params accepted by MangaDex's feed endpoint.
+type GetChaptersParams struct {
+	Page     int
+	Limit    int
+	Language string
+}
+
+type apiResponse struct {
+	Data interface{} `json:"data"`
+}
+
+// GetManga fetches a manga's metadata by ID.
+func (c *Client) GetManga(id string) (*Manga, error) {
+	var raw struct {
+		Data struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Title map[string]string `json:"title"`
+			} `json:"attributes"`
+			Relationships []struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			} `json:"relationships"`
+		} `json:"data"`
+	}
+
+	if err := c.get(fmt.Sprintf("/manga/%s", id), nil, &raw); err != nil {
+		return nil, fmt.Errorf("get manga %s: %w", id, err)
+	}
+
+	manga := &Manga{ID: raw.Data.ID, Title: raw.Data.Attributes.Title}
+	for _, rel := range raw.Data.Relationships {
+		if rel.Type == "cover_art" {
+			manga.CoverArtID = rel.ID
+			break
+		}
+	}
+	return manga, nil
+}
+
+// GetChapters returns one page of a manga's chapter feed, optionally
+// filtered to a single language.
+func (c *Client) GetChapters(mangaID string, params GetChaptersParams) ([]Chapter, error) {
+	if params.Limit <= 0 {
+		params.Limit = 100
+	}
+
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(params.Limit))
+	q.Set("offset", strconv.Itoa(params.Page*params.Limit))
+	if params.Language != "" {
+		q.Set("translatedLanguage[]", params.Language)
+	}
+
+	var raw struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Chapter            string `json:"chapter"`
+				Title              string `json:"title"`
+				TranslatedLanguage string `json:"translatedLanguage"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := c.get(fmt.Sprintf("/manga/%s/feed", mangaID), q, &raw); err != nil {
+		return nil, fmt.Errorf("get chapters for %s: %w", mangaID, err)
+	}
+
+	chapters := make([]Chapter, 0, len(raw.Data))
+	for _, d := range raw.Data {
+		chapters = append(chapters, Chapter{
+			ID:       d.ID,
+			Number:   d.Attributes.Chapter,
+			Title:    d.Attributes.Title,
+			Language: d.Attributes.TranslatedLanguage,
+		})
+	}
+	return chapters, nil
+}
+
+// GetChapter fetches the at-home page listing for a single chapter, used to
+// build the full-resolution page image URLs.
+func (c *Client) GetChapter(chapterID string) (*ChapterPages, error) {
+	var pages ChapterPages
+	if err := c.get(fmt.Sprintf("/at-home/server/%s", chapterID), nil, &pages); err != nil {
+		return nil, fmt.Errorf("get chapter %s: %w", chapterID, err)
+	}
+	return &pages, nil
+}
+
+// GetCovers returns the cover_art entries for a manga.
+func (c *Client) GetCovers(mangaID string) ([]Cover, error) {
+	q := url.Values{}
+	q.Set("manga[]", mangaID)
+
+	var raw struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				FileName string `json:"fileName"`
+				Locale   string `json:"locale"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	if err := c.get("/cover", q, &raw); err != nil {
+		return nil, fmt.Errorf("get covers for %s: %w", mangaID, err)
+	}
+
+	covers := make([]Cover, 0, len(raw.Data))
+	for _, d := range raw.Data {
+		covers = append(covers, Cover{ID: d.ID, FileName: d.Attributes.FileName, Locale: d.Attributes.Locale})
+	}
+	return covers, nil
+}
+
+// PageURL builds the full-resolution image URL for a page returned by
+// GetChapter, suitable for downloading.
+func (p *ChapterPages) PageURL(index int) string {
+	return fmt.Sprintf("%s/data/%s/%s", p.BaseURL, p.Chapter.Hash, p.Chapter.Data[index])
+}
+
+func (c *Client) get(path string, query url.Values, out interface{}) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	data, err := c.Download(u)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// Download fetches rawURL's body, going through Cache when one is
+// configured.
+func (c *Client) Download(rawURL string) ([]byte, error) {
+	if c.Cache != nil {
+		return c.Cache.Fetch(rawURL)
+	}
+
+	resp, err := c.HTTPClient.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, rawURL)
+	}
+
+	return io.ReadAll(resp.Body)
+}
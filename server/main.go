@@ -1,27 +1,45 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 
+	"github.com/smoreg/mangaoff-server/activitypub"
+	"github.com/smoreg/mangaoff-server/cache"
 	"github.com/smoreg/mangaoff-server/handlers"
+	"github.com/smoreg/mangaoff-server/importer"
+	"github.com/smoreg/mangaoff-server/opds"
 )
 
 func main() {
 	port := flag.Int("port", 8080, "HTTP server port")
 	dataDir := flag.String("data", "/opt/mangaoff/data", "Data directory path")
+	cacheDir := flag.String("cache-dir", "", "Fetch cache directory (default: OS cache dir)/mangaoff")
+	cacheTTL := flag.Duration("cache-ttl", time.Hour, "Fetch cache entry TTL")
+	apDomain := flag.String("ap-domain", "", "Canonical hostname for the ActivityPub actor (disables ActivityPub if empty)")
+	apPollInterval := flag.Duration("ap-poll-interval", 5*time.Minute, "How often the ActivityPub poller scans for new chapters")
 	flag.Parse()
 
 	// Verify data directory exists
 	if _, err := os.Stat(*dataDir); os.IsNotExist(err) {
-		log.Printf("Warning: data directory does not exist: %s", *dataDir)
+		log.Printf("Warning: data directory does not exist, creating it: %s", *dataDir)
+		if err := os.MkdirAll(*dataDir, 0o755); err != nil {
+			log.Fatalf("Failed to create data directory: %v", err)
+		}
+	}
+
+	fetchCache, err := newCache(*cacheDir, *cacheTTL)
+	if err != nil {
+		log.Fatalf("Failed to set up cache: %v", err)
 	}
 
 	r := chi.NewRouter()
@@ -44,14 +62,59 @@ func main() {
 		w.Write([]byte("OK"))
 	})
 
+	mangaIndex, err := handlers.NewMangaIndex(*dataDir)
+	if err != nil {
+		log.Fatalf("Failed to build manga index: %v", err)
+	}
+
 	// API routes
 	mangaHandler := handlers.NewMangaHandler(*dataDir)
+	mangaHandler.Cache = fetchCache
+	mangaHandler.Index = mangaIndex
+
+	mangadexClient := importer.NewClient()
+	mangadexClient.Cache = fetchCache
+	im := importer.New(mangadexClient, *dataDir)
+	im.Cache = fetchCache
+	importHandler := handlers.NewImportHandler(im)
+
+	cacheHandler := handlers.NewCacheHandler(fetchCache)
 
 	r.Route("/api/v1", func(r chi.Router) {
 		r.Get("/manga", mangaHandler.ListManga)
 		r.Get("/manga/{id}", mangaHandler.GetManga)
+		r.Post("/import/mangadex/{id}", importHandler.ImportMangaDex)
+		r.Post("/cache/purge", cacheHandler.Purge)
+		r.Get("/manga/{id}/chapters/{num}/{lang}/pages", mangaHandler.ListPages)
+		r.Get("/manga/{id}/chapters/{num}/{lang}/pages/{n}", mangaHandler.GetPage)
+	})
+
+	// OPDS catalog for e-reader clients
+	opdsHandler := opds.NewHandler(*dataDir, mangaIndex)
+	r.Route("/opds", func(r chi.Router) {
+		r.Get("/", opdsHandler.Root)
+		r.Get("/manga/{id}", opdsHandler.Manga)
+		r.Get("/manga/{id}/cover", opdsHandler.Cover)
+		r.Get("/manga/{id}/chapters/{num}/{lang}", opdsHandler.Chapter)
 	})
 
+	// ActivityPub actor that announces new chapters to Fediverse followers
+	if *apDomain != "" {
+		actor, err := activitypub.NewActor(*dataDir, *apDomain)
+		if err != nil {
+			log.Fatalf("Failed to set up ActivityPub actor: %v", err)
+		}
+
+		r.Get("/ap/actor", actor.ServeActor)
+		r.Get("/ap/outbox", actor.ServeOutbox)
+		r.Post("/ap/inbox", actor.ServeInbox)
+		r.Get("/.well-known/webfinger", actor.ServeWebfinger)
+		r.Get("/.well-known/host-meta", actor.ServeHostMeta)
+
+		poller := activitypub.NewPoller(actor, *apPollInterval)
+		go poller.Run(context.Background())
+	}
+
 	addr := fmt.Sprintf(":%d", *port)
 	log.Printf("Starting server on %s", addr)
 	log.Printf("Data directory: %s", *dataDir)
@@ -60,3 +123,16 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// newCache builds the fetch cache, falling back to the OS cache directory
+// when dir is empty.
+func newCache(dir string, ttl time.Duration) (*cache.Cache, error) {
+	if dir == "" {
+		var err error
+		dir, err = cache.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cache.New(dir, ttl), nil
+}
@@ -0,0 +1,55 @@
+// Package opds exposes the manga library as an OPDS 1.2 (Atom-based)
+// acquisition catalog, so e-reader clients like Chunky, KyBook, or Panels
+// can browse and download chapters without understanding the JSON API.
+package opds
+
+import "encoding/xml"
+
+const (
+	nsAtom = "http://www.w3.org/2005/Atom"
+
+	navigationType   = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	acquisitionType  = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	comicArchiveType = "application/vnd.comicbook+zip"
+
+	relSubsection  = "subsection"
+	relAcquisition = "http://opds-spec.org/acquisition"
+	relThumbnail   = "http://opds-spec.org/image/thumbnail"
+)
+
+// feed is an OPDS/Atom acquisition or navigation feed.
+type feed struct {
+	XMLName xml.Name `xml:"feed"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Links   []link   `xml:"link"`
+	Entries []entry  `xml:"entry"`
+}
+
+type link struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type entry struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Content string `xml:"content,omitempty"`
+	Links   []link `xml:"link"`
+}
+
+func newFeed(id, title string, links []link, entries []entry) feed {
+	return feed{
+		Xmlns:   nsAtom,
+		ID:      id,
+		Title:   title,
+		Updated: atomNow(),
+		Links:   links,
+		Entries: entries,
+	}
+}
@@ -0,0 +1,172 @@
+package opds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/smoreg/mangaoff-server/handlers"
+)
+
+// Handler serves the OPDS catalog over the same DataDir layout the JSON API
+// uses.
+type Handler struct {
+	DataDir string
+	// Index backs Root's catalog listing so it doesn't re-scan every
+	// manifest.json on disk on every request. It must be set; see
+	// handlers.NewMangaIndex.
+	Index *handlers.MangaIndex
+}
+
+// NewHandler returns an OPDS Handler rooted at dataDir, listing manga from
+// index.
+func NewHandler(dataDir string, index *handlers.MangaIndex) *Handler {
+	return &Handler{DataDir: dataDir, Index: index}
+}
+
+// Root handles GET /opds, listing every manga in the index as a navigation
+// entry pointing at its acquisition feed.
+func (h *Handler) Root(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r)
+	var feedEntries []entry
+	for _, item := range h.Index.All() {
+		links := []link{
+			{Rel: relSubsection, Href: fmt.Sprintf("%s/opds/manga/%s", base, item.ID), Type: acquisitionType},
+		}
+		if item.Cover != "" {
+			links = append(links, link{
+				Rel:  relThumbnail,
+				Href: fmt.Sprintf("%s/opds/manga/%s/cover", base, item.ID),
+			})
+		}
+
+		feedEntries = append(feedEntries, entry{
+			ID:      "urn:mangaoff:manga:" + item.ID,
+			Title:   item.Title,
+			Updated: atomNow(),
+			Links:   links,
+		})
+	}
+
+	writeFeed(w, newFeed("urn:mangaoff:root", "Manga Library", []link{
+		{Rel: "self", Href: base + "/opds", Type: navigationType},
+	}, feedEntries))
+}
+
+// Manga handles GET /opds/manga/{id}, listing every chapter/language
+// combination as an acquisition entry.
+func (h *Handler) Manga(w http.ResponseWriter, r *http.Request) {
+	mangaID := chi.URLParam(r, "id")
+	manifest, err := loadManifest(filepath.Join(h.DataDir, mangaID, "manifest.json"))
+	if err != nil {
+		http.Error(w, "Manga not found", http.StatusNotFound)
+		return
+	}
+
+	base := baseURL(r)
+	var feedEntries []entry
+	for _, ch := range manifest.Chapters {
+		for lang := range ch.Languages {
+			href := fmt.Sprintf("%s/opds/manga/%s/chapters/%s/%s", base, mangaID, ch.Number, lang)
+			feedEntries = append(feedEntries, entry{
+				ID:      fmt.Sprintf("urn:mangaoff:chapter:%s:%s:%s", mangaID, ch.Number, lang),
+				Title:   fmt.Sprintf("Chapter %s (%s)", ch.Number, lang),
+				Updated: atomNow(),
+				Links: []link{
+					{Rel: relAcquisition, Href: href, Type: comicArchiveType},
+				},
+			})
+		}
+	}
+
+	writeFeed(w, newFeed("urn:mangaoff:manga:"+mangaID, manifest.Manga.Title, []link{
+		{Rel: "self", Href: fmt.Sprintf("%s/opds/manga/%s", base, mangaID), Type: acquisitionType},
+	}, feedEntries))
+}
+
+// Cover handles GET /opds/manga/{id}/cover, streaming the manga's cover
+// image — this is the href Root's thumbnail links point at.
+func (h *Handler) Cover(w http.ResponseWriter, r *http.Request) {
+	mangaID := chi.URLParam(r, "id")
+	manifest, err := loadManifest(filepath.Join(h.DataDir, mangaID, "manifest.json"))
+	if err != nil {
+		http.Error(w, "Manga not found", http.StatusNotFound)
+		return
+	}
+	if manifest.Manga.Cover == "" {
+		http.Error(w, "No cover available", http.StatusNotFound)
+		return
+	}
+
+	http.ServeFile(w, r, filepath.Join(h.DataDir, mangaID, manifest.Manga.Cover))
+}
+
+// Chapter handles GET /opds/manga/{id}/chapters/{num}/{lang}, streaming the
+// chapter archive itself — this is the href acquisition links in Manga
+// point at.
+func (h *Handler) Chapter(w http.ResponseWriter, r *http.Request) {
+	mangaID := chi.URLParam(r, "id")
+	number := chi.URLParam(r, "num")
+	lang := chi.URLParam(r, "lang")
+
+	manifest, err := loadManifest(filepath.Join(h.DataDir, mangaID, "manifest.json"))
+	if err != nil {
+		http.Error(w, "Manga not found", http.StatusNotFound)
+		return
+	}
+
+	for _, ch := range manifest.Chapters {
+		if ch.Number != number {
+			continue
+		}
+		info, ok := ch.Languages[lang]
+		if !ok {
+			break
+		}
+
+		archivePath := filepath.Join(h.DataDir, mangaID, info.Archive)
+		w.Header().Set("Content-Type", comicArchiveType)
+		http.ServeFile(w, r, archivePath)
+		return
+	}
+
+	http.Error(w, "Chapter not found", http.StatusNotFound)
+}
+
+func loadManifest(path string) (*handlers.Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var manifest handlers.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+func writeFeed(w http.ResponseWriter, f feed) {
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	enc.Encode(f)
+}
+
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func atomNow() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}